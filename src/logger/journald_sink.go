@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path"
+	"strings"
+)
+
+// journaldSocket is the well-known systemd-journald datagram socket.
+const journaldSocket = "/run/systemd/journal/socket"
+
+// journaldPriority maps a LogLevel to a journal/syslog PRIORITY value.
+var journaldPriority = map[LogLevel]int{
+	DEBUG: 7,
+	INFO:  6,
+	WARN:  4,
+	ERROR: 3,
+	FATAL: 0,
+}
+
+// JournaldSink returns a decorator that registers a LogSystem forwarding
+// records to the local systemd-journald over its native datagram protocol.
+// If the journal socket can't be reached, it reports the error to stderr and
+// leaves the logger's other sinks intact.
+func JournaldSink() func(Logger) Logger {
+	return func(l Logger) Logger {
+		conn, err := net.Dial("unixgram", journaldSocket)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return l
+		}
+		l.sinks = append(l.sinks, &journaldSink{conn: conn, identifier: path.Base(os.Args[0])})
+		return l
+	}
+}
+
+type journaldSink struct {
+	conn       net.Conn
+	identifier string
+	level      LogLevel
+}
+
+func (s *journaldSink) SetLogLevel(level LogLevel) {
+	s.level = level
+}
+
+func (s *journaldSink) Write(level LogLevel, msg string) {
+	if level < s.level {
+		return
+	}
+	var buf bytes.Buffer
+	writeJournalField(&buf, "PRIORITY", fmt.Sprintf("%d", journaldPriority[level]))
+	writeJournalField(&buf, "SYSLOG_IDENTIFIER", s.identifier)
+	writeJournalField(&buf, "MESSAGE", msg)
+	s.conn.Write(buf.Bytes())
+}
+
+// writeJournalField appends one field to buf using systemd's journal native
+// protocol: "KEY=value\n" for values without embedded newlines, or
+// "KEY\n" + little-endian uint64 length + value + "\n" otherwise.
+func writeJournalField(buf *bytes.Buffer, key, value string) {
+	if strings.ContainsRune(value, '\n') {
+		buf.WriteString(key)
+		buf.WriteByte('\n')
+		binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteString(key)
+	buf.WriteByte('=')
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
@@ -2,10 +2,11 @@ package logger
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"path"
 	"runtime"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 )
@@ -45,53 +46,129 @@ func Start(decorators ...func(Logger) Logger) Logger {
 		for _, decorator := range decorators {
 			loggerInstance = decorator(loggerInstance)
 		}
-		var logger *log.Logger
-		var segment *logSegment
 		if loggerInstance.logPath != "" {
-			segment = newLogSegment(loggerInstance.unit, loggerInstance.logPath)
+			loggerInstance.segment = newLogSegment(loggerInstance.unit, loggerInstance.logPath, loggerInstance.maxFileSize)
 		}
-		if segment != nil {
-			logger = log.New(segment, "", log.LstdFlags)
+		if loggerInstance.segment != nil {
+			loggerInstance.sinks = append(loggerInstance.sinks, newWriterSink(loggerInstance.segment))
+			if loggerInstance.isStdout {
+				loggerInstance.sinks = append(loggerInstance.sinks, newWriterSink(os.Stdout))
+			}
 		} else if loggerInstance.isStdout {
-			logger = log.New(os.Stdout, "", log.LstdFlags)
-		} else {
-			logger = log.New(os.Stderr, "", log.LstdFlags)
+			loggerInstance.sinks = append(loggerInstance.sinks, newWriterSink(os.Stdout))
+		} else if len(loggerInstance.sinks) == 0 {
+			loggerInstance.sinks = append(loggerInstance.sinks, newWriterSink(os.Stderr))
+		}
+		loggerInstance.stopped = new(int32)
+		loggerInstance.stopMu = new(sync.RWMutex)
+		loggerInstance.msgCh = make(chan logMessage, msgChanSize)
+		loggerInstance.quit = make(chan struct{})
+		loggerInstance.done = make(chan struct{})
+		go loggerInstance.dispatchLoop()
+		if loggerInstance.segment != nil && (loggerInstance.compress || loggerInstance.maxAge > 0 || loggerInstance.maxTotalSize > 0) {
+			loggerInstance.retentionQuit = make(chan struct{})
+			loggerInstance.retentionDone = make(chan struct{})
+			go loggerInstance.retentionLoop()
 		}
-		loggerInstance.logger = logger
 		return loggerInstance
 	}
 	panic("Start() already called")
 }
 
-// Stop stops the logger.
+// RegisterSink adds sink to the set of LogSystems that receive every record
+// dispatched by the logger, in addition to the sink derived from
+// LogFilePath/AlsoStdout. It must be supplied as a decorator to Start.
+func RegisterSink(sink LogSystem) func(Logger) Logger {
+	return func(l Logger) Logger {
+		l.sinks = append(l.sinks, sink)
+		return l
+	}
+}
+
+// Stop stops the logger, draining any buffered records before closing the
+// underlying segment. l.stopped and l.stopMu are pointers shared by every
+// copy of Logger handed out since Start, guarding both against a repeat
+// Stop() and against racing an in-flight emit (see emit's stopMu use).
 func (l Logger) Stop() {
-	if atomic.CompareAndSwapInt32(&l.stopped, 0, 1) {
-		if l.printStack {
-			traceInfo := make([]byte, 1<<16)
-			n := runtime.Stack(traceInfo, true)
-			l.logger.Printf("%s", traceInfo[:n])
-			if l.isStdout {
-				log.Printf("%s", traceInfo[:n])
-			}
+	l.stopMu.Lock()
+	stopping := atomic.CompareAndSwapInt32(l.stopped, 0, 1)
+	l.stopMu.Unlock()
+	if !stopping {
+		return
+	}
+	close(l.quit)
+	<-l.done
+	if l.printStack {
+		traceInfo := make([]byte, 1<<16)
+		n := runtime.Stack(traceInfo, true)
+		fanOut(l.sinks, FATAL, string(traceInfo[:n]))
+	}
+	for _, sink := range l.sinks {
+		if c, ok := sink.(closer); ok {
+			c.close()
 		}
-		if l.segment != nil {
-			l.segment.Close()
+	}
+	if l.retentionQuit != nil {
+		close(l.retentionQuit)
+		<-l.retentionDone
+	}
+	if l.segment != nil {
+		l.segment.Close()
+	}
+	l.segment = nil
+	atomic.StoreInt32(&started, 0)
+}
+
+// dispatchLoop is the only goroutine that writes to a sink; it drains msgCh
+// and fans each record out, then drains whatever's left on quit before
+// returning so Stop can wait for pending writes to finish.
+func (l Logger) dispatchLoop() {
+	defer close(l.done)
+	for {
+		select {
+		case m := <-l.msgCh:
+			deliver(l.sinks, m)
+		case <-l.quit:
+			for {
+				select {
+				case m := <-l.msgCh:
+					deliver(l.sinks, m)
+				default:
+					return
+				}
+			}
 		}
-		l.segment = nil
-		l.logger = nil
-		atomic.StoreInt32(&started, 0)
 	}
 }
 
-// logSegment implements io.Writer
+// deliver fans m out to sinks and, if m carries an ack (the FATAL flush
+// path), closes it afterward so the waiting caller knows m and everything
+// enqueued ahead of it has been written.
+func deliver(sinks []LogSystem, m logMessage) {
+	fanOut(sinks, m.level, m.msg)
+	if m.ack != nil {
+		close(m.ack)
+	}
+}
+
+// logSegment implements io.Writer. logFile/bytesWritten/curMinute/seq are
+// mutated by Write/rotate (called from dispatchLoop) and read by
+// activeName (called from the retention goroutine), so mu guards all of
+// them.
 type logSegment struct {
 	unit         time.Duration
 	logPath      string
-	logFile      *os.File
 	timeToCreate <-chan time.Time
+	maxFileSize  int64
+
+	mu           sync.Mutex
+	logFile      *os.File
+	bytesWritten int64
+	curMinute    string
+	seq          int
 }
 
-func newLogSegment(unit time.Duration, logPath string) *logSegment {
+func newLogSegment(unit time.Duration, logPath string, maxFileSize int64) *logSegment {
 	now := time.Now()
 	if logPath != "" {
 		err := os.MkdirAll(logPath, os.ModePerm)
@@ -99,7 +176,7 @@ func newLogSegment(unit time.Duration, logPath string) *logSegment {
 			fmt.Fprintln(os.Stderr, err)
 			return nil
 		}
-		name := getLogFileName(time.Now())
+		name := getLogFileName(now, 0)
 		logFile, err := os.OpenFile(path.Join(logPath, name), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
 		if err != nil {
 			if os.IsNotExist(err) {
@@ -113,9 +190,9 @@ func newLogSegment(unit time.Duration, logPath string) *logSegment {
 				return nil
 			}
 		}
-		next := now.Truncate(unit).Add(unit)
 		var timeToCreate <-chan time.Time
 		if unit == time.Hour || unit == time.Minute {
+			next := now.Truncate(unit).Add(unit)
 			timeToCreate = time.After(next.Sub(time.Now()))
 		}
 		return &logSegment{
@@ -123,96 +200,133 @@ func newLogSegment(unit time.Duration, logPath string) *logSegment {
 			logPath:      logPath,
 			logFile:      logFile,
 			timeToCreate: timeToCreate,
+			maxFileSize:  maxFileSize,
+			curMinute:    minuteKey(now),
 		}
 	}
 	return nil
 }
 
 func (ls *logSegment) Write(p []byte) (n int, err error) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
 	if ls.timeToCreate != nil && ls.logFile != os.Stdout && ls.logFile != os.Stderr {
 		select {
 		case current := <-ls.timeToCreate:
-			ls.logFile.Close()
-			ls.logFile = nil
-			name := getLogFileName(current)
-			ls.logFile, err = os.Create(path.Join(ls.logPath, name))
-			if err != nil {
-				// logger into stderr if we can't create new file
-				fmt.Fprintln(os.Stderr, err)
-				ls.logFile = os.Stderr
-			} else {
-				next := current.Truncate(ls.unit).Add(ls.unit)
-				ls.timeToCreate = time.After(next.Sub(time.Now()))
-			}
+			ls.rotateLocked(current)
 		default:
 			// do nothing
 		}
 	}
-	return ls.logFile.Write(p)
+	if ls.maxFileSize > 0 && ls.bytesWritten+int64(len(p)) > ls.maxFileSize &&
+		ls.logFile != os.Stdout && ls.logFile != os.Stderr {
+		ls.rotateLocked(time.Now())
+	}
+	n, err = ls.logFile.Write(p)
+	ls.bytesWritten += int64(n)
+	return n, err
+}
+
+// rotateLocked closes the current file and opens the next one named after
+// t, bumping a per-minute sequence number so two rotations in the same
+// wall-clock minute don't collide on the same getLogFileName. Callers must
+// hold ls.mu.
+func (ls *logSegment) rotateLocked(t time.Time) {
+	ls.logFile.Close()
+	if key := minuteKey(t); key == ls.curMinute {
+		ls.seq++
+	} else {
+		ls.curMinute = key
+		ls.seq = 0
+	}
+	name := getLogFileName(t, ls.seq)
+	logFile, err := os.Create(path.Join(ls.logPath, name))
+	if err != nil {
+		// logger into stderr if we can't create new file
+		fmt.Fprintln(os.Stderr, err)
+		ls.logFile = os.Stderr
+		return
+	}
+	ls.logFile = logFile
+	ls.bytesWritten = 0
+	if ls.unit == time.Hour || ls.unit == time.Minute {
+		next := t.Truncate(ls.unit).Add(ls.unit)
+		ls.timeToCreate = time.After(next.Sub(time.Now()))
+	}
 }
 
 func (ls *logSegment) Close() {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
 	ls.logFile.Close()
 }
 
-func getLogFileName(t time.Time) string {
+// activeName returns the base name of the file currently being written,
+// for the retention worker to recognize and skip the in-use segment.
+func (ls *logSegment) activeName() string {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if ls.logFile == nil {
+		return ""
+	}
+	return path.Base(ls.logFile.Name())
+}
+
+func minuteKey(t time.Time) string {
+	return t.Format("200601021504")
+}
+
+func getLogFileName(t time.Time, seq int) string {
 	proc := path.Base(os.Args[0])
-	now := time.Now()
-	year := now.Year()
-	month := now.Month()
-	day := now.Day()
-	hour := now.Hour()
-	minute := now.Minute()
 	pid := os.Getpid()
+	if seq > 0 {
+		return fmt.Sprintf("%s.%04d-%02d-%02d-%02d-%02d.%d.%d.logger",
+			proc, t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), pid, seq)
+	}
 	return fmt.Sprintf("%s.%04d-%02d-%02d-%02d-%02d.%d.logger",
-		proc, year, month, day, hour, minute, pid)
+		proc, t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), pid)
 }
 
 // Logger is the logger type.
 type Logger struct {
-	logger     *log.Logger
 	level      LogLevel
+	sinks      []LogSystem
 	segment    *logSegment
-	stopped    int32
+	stopped    *int32
+	stopMu     *sync.RWMutex
 	logPath    string
 	unit       time.Duration
 	isStdout   bool
 	printStack bool
+	jsonFormat bool
+	fields     []Field
+	msgCh      chan logMessage
+	quit       chan struct{}
+	done       chan struct{}
+
+	maxFileSize   int64
+	maxAge        time.Duration
+	maxTotalSize  int64
+	compress      bool
+	retentionQuit chan struct{}
+	retentionDone chan struct{}
 }
 
 func (l Logger) doPrintf(level LogLevel, format string, v ...interface{}) {
-	if l.logger == nil {
+	if l.msgCh == nil || level < l.level {
 		return
 	}
-	if level >= l.level {
-		funcName, fileName, lineNum := getRuntimeInfo()
-		format = fmt.Sprintf("%5s [%s] (%s:%d) - %s", tagName[level], path.Base(funcName), path.Base(fileName), lineNum, format)
-		l.logger.Printf(format, v...)
-		if l.isStdout {
-			log.Printf(format, v...)
-		}
-		if level == FATAL {
-			os.Exit(1)
-		}
-	}
+	funcName, fileName, lineNum := getRuntimeInfo()
+	l.emit(level, funcName, fileName, lineNum, fmt.Sprintf(format, v...), l.fields)
 }
 
 func (l Logger) doPrintln(level LogLevel, v ...interface{}) {
-	if l.logger == nil {
+	if l.msgCh == nil || level < l.level {
 		return
 	}
-	if level >= l.level {
-		funcName, fileName, lineNum := getRuntimeInfo()
-		prefix := fmt.Sprintf("%5s [%s] (%s:%d) - ", tagName[level], path.Base(funcName), path.Base(fileName), lineNum)
-		value := fmt.Sprintf("%s%s", prefix, fmt.Sprintln(v...))
-		l.logger.Print(value)
-		if l.isStdout {
-			log.Print(value)
-		}
-		if level == FATAL {
-			os.Exit(1)
-		}
-	}
+	funcName, fileName, lineNum := getRuntimeInfo()
+	msg := strings.TrimSuffix(fmt.Sprintln(v...), "\n")
+	l.emit(level, funcName, fileName, lineNum, msg, l.fields)
 }
 
 func getRuntimeInfo() (string, string, int) {
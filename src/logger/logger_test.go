@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+)
+
+// TestEmitNoopsAfterStop guards against a post-Stop log call sitting in
+// msgCh forever since dispatchLoop is gone.
+func TestEmitNoopsAfterStop(t *testing.T) {
+	l := Start(LogFilePath(t.TempDir()))
+	l.Stop()
+
+	Infof("after stop: %d", 1)
+	Infoln("after stop")
+}
+
+// TestEmitStopRace exercises emit racing Stop: every Infof must either land
+// before dispatchLoop exits or no-op, never block forever on a dispatchLoop
+// that's already gone.
+func TestEmitStopRace(t *testing.T) {
+	l := Start(LogFilePath(t.TempDir()))
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Infof("racing")
+		}()
+	}
+	l.Stop()
+	wg.Wait()
+}
+
+// TestStopWithPrintStackAndRemoteSink guards against Stop closing a
+// remoteSink's queue before flushing the PrintStack record, which used to
+// panic with "send on closed channel".
+func TestStopWithPrintStackAndRemoteSink(t *testing.T) {
+	l := Start(LogFilePath(t.TempDir()), PrintStack, RemoteSink("tcp", "127.0.0.1:1"))
+	l.Stop()
+}
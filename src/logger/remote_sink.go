@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// remoteQueueSize bounds how many formatted records a remoteSink holds
+// while its own goroutine is busy dialing or blocked on a slow write.
+const remoteQueueSize = 256
+
+// RemoteOption configures a remoteSink built by RemoteSink.
+type RemoteOption func(*remoteSink)
+
+// RemoteDialTimeout bounds how long a single (re)connect attempt may take.
+func RemoteDialTimeout(d time.Duration) RemoteOption {
+	return func(s *remoteSink) { s.dialTimeout = d }
+}
+
+// RemoteMaxBackoff caps the delay between reconnect attempts after the
+// connection drops.
+func RemoteMaxBackoff(d time.Duration) RemoteOption {
+	return func(s *remoteSink) { s.maxBackoff = d }
+}
+
+// RemoteSink returns a decorator that registers a LogSystem forwarding
+// records, one per line, to a remote host over network ("tcp" or "udp").
+// Write only enqueues; a dedicated goroutine owns the connection, dialing
+// lazily and redialing with exponential backoff, capped at maxBackoff,
+// whenever it drops.
+func RemoteSink(network, addr string, opts ...RemoteOption) func(Logger) Logger {
+	s := &remoteSink{
+		network:     network,
+		addr:        addr,
+		dialTimeout: 5 * time.Second,
+		maxBackoff:  30 * time.Second,
+		queue:       make(chan string, remoteQueueSize),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	go s.run()
+	return func(l Logger) Logger {
+		l.sinks = append(l.sinks, s)
+		return l
+	}
+}
+
+type remoteSink struct {
+	network     string
+	addr        string
+	dialTimeout time.Duration
+	maxBackoff  time.Duration
+	level       LogLevel
+	queue       chan string
+}
+
+func (s *remoteSink) SetLogLevel(level LogLevel) {
+	s.level = level
+}
+
+// close tells run to drain whatever is queued and exit, so Stop doesn't
+// leave the dial/retry goroutine or its connection running forever.
+func (s *remoteSink) close() {
+	close(s.queue)
+}
+
+// Write only ever enqueues, never dials or does network I/O itself. Once
+// the queue is full, a stalled endpoint makes it drop records rather than
+// block the caller.
+func (s *remoteSink) Write(level LogLevel, msg string) {
+	if level < s.level {
+		return
+	}
+	select {
+	case s.queue <- msg:
+	default:
+	}
+}
+
+// run owns the connection: it reads queued records one at a time,
+// (re)dialing with backoff as needed, until close makes queue run dry.
+func (s *remoteSink) run() {
+	var conn net.Conn
+	var backoff time.Duration
+	defer func() {
+		if conn != nil {
+			conn.Close()
+		}
+	}()
+	for msg := range s.queue {
+		for conn == nil {
+			c, err := net.DialTimeout(s.network, s.addr, s.dialTimeout)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				backoff = nextBackoff(backoff, s.maxBackoff)
+				time.Sleep(backoff)
+				continue
+			}
+			conn = c
+			backoff = 0
+		}
+		if _, err := fmt.Fprintf(conn, "%s\n", msg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			conn.Close()
+			conn = nil
+		}
+	}
+}
+
+// nextBackoff doubles cur (starting at one second), capped at max.
+func nextBackoff(cur, max time.Duration) time.Duration {
+	if cur == 0 {
+		cur = time.Second
+	} else {
+		cur *= 2
+	}
+	if cur > max {
+		return max
+	}
+	return cur
+}
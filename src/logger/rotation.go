@@ -0,0 +1,159 @@
+package logger
+
+import (
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// retentionInterval is how often the retention worker re-scans logPath.
+const retentionInterval = time.Minute
+
+// compressAfter is how long a rotated-away segment sits on disk before the
+// retention worker gzips it, giving anything still tailing the raw file
+// (log shippers, a human with `tail -f`) a short grace window.
+const compressAfter = 5 * time.Minute
+
+// MaxFileSize rotates the active log file before a write would take it past
+// bytes, in addition to any EveryHour/EveryMinute wall-clock rotation.
+func MaxFileSize(bytes int64) func(Logger) Logger {
+	return func(l Logger) Logger {
+		l.maxFileSize = bytes
+		return l
+	}
+}
+
+// MaxAge deletes rotated log files (and, if Compress is set, their .gz
+// archives) once they are older than d.
+func MaxAge(d time.Duration) func(Logger) Logger {
+	return func(l Logger) Logger {
+		l.maxAge = d
+		return l
+	}
+}
+
+// MaxTotalSize deletes rotated log files and .gz archives under logPath,
+// starting from the oldest, while their combined size exceeds bytes. The
+// active log file is not counted toward that total and is never deleted.
+func MaxTotalSize(bytes int64) func(Logger) Logger {
+	return func(l Logger) Logger {
+		l.maxTotalSize = bytes
+		return l
+	}
+}
+
+// Compress gzips rotated log files once they've sat on disk for
+// compressAfter, and folds the resulting .gz archives into MaxAge/
+// MaxTotalSize accounting.
+func Compress(l Logger) Logger {
+	l.compress = true
+	return l
+}
+
+// retentionLoop periodically compresses and prunes rotated log files under
+// logPath. It is started from Start and stopped from Stop, which closes
+// retentionQuit and waits on retentionDone.
+func (l Logger) retentionLoop() {
+	defer close(l.retentionDone)
+	ticker := time.NewTicker(retentionInterval)
+	defer ticker.Stop()
+	for {
+		l.runRetention()
+		select {
+		case <-ticker.C:
+		case <-l.retentionQuit:
+			return
+		}
+	}
+}
+
+func (l Logger) runRetention() {
+	entries, err := ioutil.ReadDir(l.logPath)
+	if err != nil {
+		return
+	}
+	activeName := ""
+	if l.segment != nil {
+		activeName = l.segment.activeName()
+	}
+	var archives []os.FileInfo
+	now := time.Now()
+	for _, info := range entries {
+		if info.IsDir() || info.Name() == activeName {
+			continue
+		}
+		if l.compress && isRotatedLogFile(info.Name()) && now.Sub(info.ModTime()) >= compressAfter {
+			if err := compressFile(l.logPath, info.Name()); err == nil {
+				continue // re-picked up as a .gz on the next scan
+			}
+		}
+		if isRotatedLogFile(info.Name()) || isArchivedLogFile(info.Name()) {
+			archives = append(archives, info)
+		}
+	}
+	l.pruneArchives(archives)
+}
+
+func (l Logger) pruneArchives(archives []os.FileInfo) {
+	sort.Slice(archives, func(i, j int) bool {
+		return archives[i].ModTime().Before(archives[j].ModTime())
+	})
+	var total int64
+	for _, info := range archives {
+		total += info.Size()
+	}
+	for _, info := range archives {
+		expired := l.maxAge > 0 && time.Since(info.ModTime()) > l.maxAge
+		overCap := l.maxTotalSize > 0 && total > l.maxTotalSize
+		if !expired && !overCap {
+			continue
+		}
+		if os.Remove(path.Join(l.logPath, info.Name())) == nil {
+			total -= info.Size()
+		}
+	}
+}
+
+func isRotatedLogFile(name string) bool {
+	return strings.HasSuffix(name, ".logger")
+}
+
+func isArchivedLogFile(name string) bool {
+	return strings.HasSuffix(name, ".logger.gz")
+}
+
+func compressFile(dir, name string) error {
+	src, err := os.Open(path.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dstPath := path.Join(dir, name+".gz")
+	dst, err := os.Create(dstPath)
+	if err != nil {
+		return err
+	}
+	gw := gzip.NewWriter(dst)
+	if _, err := io.Copy(gw, src); err != nil {
+		gw.Close()
+		dst.Close()
+		os.Remove(dstPath)
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		dst.Close()
+		os.Remove(dstPath)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(dstPath)
+		return err
+	}
+	return os.Remove(path.Join(dir, name))
+}
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"os"
+	"path"
+	"testing"
+	"time"
+)
+
+// TestRotateLockedSequenceNumbers guards against getLogFileName colliding
+// when rotateLocked fires more than once inside the same wall-clock minute.
+func TestRotateLockedSequenceNumbers(t *testing.T) {
+	dir := t.TempDir()
+	ls := newLogSegment(0, dir, 0)
+	if ls == nil {
+		t.Fatal("newLogSegment returned nil")
+	}
+	defer ls.Close()
+
+	now := time.Now()
+	first := path.Base(ls.logFile.Name())
+	ls.rotateLocked(now)
+	second := path.Base(ls.logFile.Name())
+	ls.rotateLocked(now)
+	third := path.Base(ls.logFile.Name())
+
+	if first == second || second == third {
+		t.Fatalf("rotateLocked produced colliding file names: %q, %q, %q", first, second, third)
+	}
+	for _, name := range []string{first, second, third} {
+		if _, err := os.Stat(path.Join(dir, name)); err != nil {
+			t.Errorf("expected %s to exist: %v", name, err)
+		}
+	}
+}
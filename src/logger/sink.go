@@ -0,0 +1,70 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+)
+
+// msgChanSize is the capacity of the buffered channel doPrintf/doPrintln
+// push onto. Fan-out to sinks happens off this channel in dispatchLoop, so
+// callers never block on sink I/O as long as the channel has room.
+const msgChanSize = 1024
+
+// logMessage is a fully formatted record waiting to be fanned out to sinks.
+// ack, if set, is closed by dispatchLoop once the record has been fanned
+// out, so the FATAL path can wait for it instead of exiting early.
+type logMessage struct {
+	level LogLevel
+	msg   string
+	ack   chan struct{}
+}
+
+// LogSystem is a log sink: something that can receive formatted records and
+// filter them by its own level, independently of the logger's global level
+// gate. RegisterSink lets callers attach any number of these to a Logger.
+type LogSystem interface {
+	Write(level LogLevel, msg string)
+	SetLogLevel(level LogLevel)
+}
+
+// closer is implemented by sinks that own a background goroutine or
+// connection Stop must shut down, such as remoteSink's queue/dial loop.
+type closer interface {
+	close()
+}
+
+// fanOut delivers msg to every sink, each of which applies its own level
+// filter.
+func fanOut(sinks []LogSystem, level LogLevel, msg string) {
+	for _, sink := range sinks {
+		sink.Write(level, msg)
+	}
+}
+
+// writerSink adapts a plain io.Writer (a *logSegment, os.Stdout, os.Stderr,
+// or any caller-supplied io.Writer) into a LogSystem.
+type writerSink struct {
+	w     io.Writer
+	level LogLevel
+}
+
+// NewWriterSink wraps w as a LogSystem with no level filter of its own
+// (DEBUG), so callers can plug in a custom io.Writer via RegisterSink.
+func NewWriterSink(w io.Writer) LogSystem {
+	return newWriterSink(w)
+}
+
+func newWriterSink(w io.Writer) *writerSink {
+	return &writerSink{w: w}
+}
+
+func (s *writerSink) Write(level LogLevel, msg string) {
+	if level < s.level {
+		return
+	}
+	fmt.Fprintf(s.w, "%s\n", msg)
+}
+
+func (s *writerSink) SetLogLevel(level LogLevel) {
+	s.level = level
+}
@@ -0,0 +1,31 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// TestWriterSinkJSONRecordIsValidJSONPerLine guards against writerSink
+// gluing its own text timestamp onto an already-complete JSON record.
+func TestWriterSinkJSONRecordIsValidJSONPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	sink := newWriterSink(&buf)
+
+	rec := encodeJSONRecord(INFO, "chatserver/main.go", 42, "message received",
+		[]Field{{Key: "conn_id", Value: 123}}, "")
+	sink.Write(INFO, rec)
+
+	line := strings.TrimSuffix(buf.String(), "\n")
+	var out map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &out); err != nil {
+		t.Fatalf("writerSink produced invalid JSON line %q: %v", line, err)
+	}
+	if out["msg"] != "message received" {
+		t.Errorf("msg = %v, want %q", out["msg"], "message received")
+	}
+	if out["conn_id"] != float64(123) {
+		t.Errorf("conn_id = %v, want 123", out["conn_id"])
+	}
+}
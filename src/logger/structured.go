@@ -0,0 +1,163 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync/atomic"
+	"time"
+)
+
+// Field is one key/value pair attached to a structured log record, via
+// either With or the trailing "key", value pairs of an *w call.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// With returns a child Logger carrying the given "key", value pairs on top
+// of the package logger's own fields, so every subsequent *w call on the
+// child automatically includes them, e.g.
+//
+//	connLogger := logger.With("conn_id", id, "remote", addr)
+//	connLogger.Infow("message received", "bytes", n)
+func With(kvs ...interface{}) Logger {
+	return loggerInstance.with(kvs...)
+}
+
+func (l Logger) with(kvs ...interface{}) Logger {
+	l.fields = append(appendFields(nil, l.fields), parseFields(kvs)...)
+	return l
+}
+
+func parseFields(kvs []interface{}) []Field {
+	fields := make([]Field, 0, len(kvs)/2)
+	for i := 0; i+1 < len(kvs); i += 2 {
+		key, ok := kvs[i].(string)
+		if !ok {
+			key = fmt.Sprint(kvs[i])
+		}
+		fields = append(fields, Field{Key: key, Value: kvs[i+1]})
+	}
+	return fields
+}
+
+func appendFields(dst, src []Field) []Field {
+	return append(dst, src...)
+}
+
+// Debugw logs msg at DEBUG level with the receiver's fields plus the
+// trailing "key", value pairs.
+func (l Logger) Debugw(msg string, kvs ...interface{}) {
+	l.doPrintw(DEBUG, msg, kvs...)
+}
+
+// Infow logs msg at INFO level with the receiver's fields plus the trailing
+// "key", value pairs.
+func (l Logger) Infow(msg string, kvs ...interface{}) {
+	l.doPrintw(INFO, msg, kvs...)
+}
+
+// Warnw logs msg at WARN level with the receiver's fields plus the trailing
+// "key", value pairs.
+func (l Logger) Warnw(msg string, kvs ...interface{}) {
+	l.doPrintw(WARN, msg, kvs...)
+}
+
+// Errorw logs msg at ERROR level with the receiver's fields plus the
+// trailing "key", value pairs.
+func (l Logger) Errorw(msg string, kvs ...interface{}) {
+	l.doPrintw(ERROR, msg, kvs...)
+}
+
+// Fatalw logs msg at FATAL level with the receiver's fields plus the
+// trailing "key", value pairs, and exits.
+func (l Logger) Fatalw(msg string, kvs ...interface{}) {
+	l.doPrintw(FATAL, msg, kvs...)
+	os.Exit(1)
+}
+
+func (l Logger) doPrintw(level LogLevel, msg string, kvs ...interface{}) {
+	if l.msgCh == nil || level < l.level {
+		return
+	}
+	funcName, fileName, lineNum := getRuntimeInfo()
+	fields := l.fields
+	if len(kvs) > 0 {
+		fields = append(appendFields(nil, l.fields), parseFields(kvs)...)
+	}
+	l.emit(level, funcName, fileName, lineNum, msg, fields)
+}
+
+// JSONFormat switches the logger's output to one JSON object per record
+// instead of the default text formatter.
+func JSONFormat(l Logger) Logger {
+	l.jsonFormat = true
+	return l
+}
+
+// emit renders level/msg/fields and enqueues the record for dispatchLoop;
+// FATAL waits for dispatchLoop to confirm the record has been flushed
+// before exiting. The stopped check and the send run under stopMu's read
+// lock so a concurrent Stop can't land between them.
+func (l Logger) emit(level LogLevel, funcName, fileName string, lineNum int, msg string, fields []Field) {
+	l.stopMu.RLock()
+	defer l.stopMu.RUnlock()
+	if l.stopped != nil && atomic.LoadInt32(l.stopped) != 0 {
+		if level == FATAL {
+			os.Exit(1)
+		}
+		return
+	}
+	rec := l.formatRecord(level, funcName, fileName, lineNum, msg, fields)
+	if level == FATAL {
+		ack := make(chan struct{})
+		l.msgCh <- logMessage{level: level, msg: rec, ack: ack}
+		<-ack
+		os.Exit(1)
+	}
+	l.msgCh <- logMessage{level: level, msg: rec}
+}
+
+func (l Logger) formatRecord(level LogLevel, funcName, fileName string, lineNum int, msg string, fields []Field) string {
+	stack := backtraceIfNeeded(fileName, lineNum)
+	if l.jsonFormat {
+		return encodeJSONRecord(level, fileName, lineNum, msg, fields, stack)
+	}
+	prefix := fmt.Sprintf("%s %5s [%s] (%s:%d) - ", time.Now().Format("2006/01/02 15:04:05"), tagName[level], path.Base(funcName), path.Base(fileName), lineNum)
+	return prefix + msg + formatFieldsText(fields) + stack
+}
+
+func formatFieldsText(fields []Field) string {
+	var s string
+	for _, f := range fields {
+		s += fmt.Sprintf(" %s=%v", f.Key, f.Value)
+	}
+	return s
+}
+
+func encodeJSONRecord(level LogLevel, fileName string, lineNum int, msg string, fields []Field, stack string) string {
+	rec := make(map[string]interface{}, 4+len(fields))
+	rec["ts"] = time.Now().Format(time.RFC3339Nano)
+	rec["level"] = tagName[level]
+	rec["caller"] = fmt.Sprintf("%s:%d", callerPath(fileName), lineNum)
+	rec["msg"] = msg
+	for _, f := range fields {
+		rec[f.Key] = f.Value
+	}
+	if stack != "" {
+		rec["stack"] = stack
+	}
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Sprintf(`{"level":"ERROR","msg":"failed to marshal log record: %s"}`, err)
+	}
+	return string(b)
+}
+
+// callerPath renders fileName as "dir/file.go", matching the chatserver/main.go
+// style callers expect in the caller field.
+func callerPath(fileName string) string {
+	return path.Join(path.Base(path.Dir(fileName)), path.Base(fileName))
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"fmt"
+	"log/syslog"
+	"os"
+)
+
+// SyslogSink returns a decorator that registers a LogSystem forwarding
+// records to the local syslog daemon under tag, mapping each LogLevel to its
+// syslog severity (DEBUG->LOG_DEBUG, INFO->LOG_INFO, WARN->LOG_WARNING,
+// ERROR->LOG_ERR, FATAL->LOG_EMERG). If the daemon can't be reached, it
+// reports the error to stderr and leaves the logger's other sinks intact,
+// the same way LogFilePath degrades when its directory can't be created.
+func SyslogSink(tag string) func(Logger) Logger {
+	return func(l Logger) Logger {
+		w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_USER, tag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return l
+		}
+		l.sinks = append(l.sinks, &syslogSink{w: w})
+		return l
+	}
+}
+
+type syslogSink struct {
+	w     *syslog.Writer
+	level LogLevel
+}
+
+func (s *syslogSink) SetLogLevel(level LogLevel) {
+	s.level = level
+}
+
+func (s *syslogSink) Write(level LogLevel, msg string) {
+	if level < s.level {
+		return
+	}
+	switch level {
+	case DEBUG:
+		s.w.Debug(msg)
+	case INFO:
+		s.w.Info(msg)
+	case WARN:
+		s.w.Warning(msg)
+	case ERROR:
+		s.w.Err(msg)
+	case FATAL:
+		s.w.Emerg(msg)
+	}
+}
@@ -0,0 +1,175 @@
+package logger
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Verbose is the boolean type returned by V. Its value is true if logging is
+// enabled at the requested verbosity for the call site, so
+//
+//	if v := logger.V(2); v {
+//	    v.Infof("...")
+//	}
+//
+// skips the Infof call, and the argument formatting it would otherwise do,
+// entirely when verbosity 2 is disabled.
+type Verbose bool
+
+// V reports whether verbosity at the given level is enabled for the caller's
+// source file, consulting any pattern installed by VModule before falling
+// back to the global verbosity level.
+func V(level int) Verbose {
+	v := int32(level) <= globalVerbosity
+	if _, fileName, _, ok := runtime.Caller(1); ok {
+		if modLevel, matched := vmodule.lookup(fileName); matched {
+			v = int32(level) <= modLevel
+		}
+	}
+	return Verbose(v)
+}
+
+// Infof prints formatted info logger if the receiver is enabled.
+func (v Verbose) Infof(format string, args ...interface{}) {
+	if v {
+		loggerInstance.doPrintf(INFO, format, args...)
+	}
+}
+
+// Infoln prints info logger if the receiver is enabled.
+func (v Verbose) Infoln(args ...interface{}) {
+	if v {
+		loggerInstance.doPrintln(INFO, args...)
+	}
+}
+
+// globalVerbosity is the verbosity level consulted by V when no VModule
+// pattern matches the caller's file.
+var globalVerbosity int32
+
+// modulePattern is one compiled entry of a VModule decorator, e.g. the
+// "chat/*=2" clause of "chatserver=3,chat/*=2".
+type modulePattern struct {
+	glob  string
+	level int32
+}
+
+// moduleVerbosity holds the compiled VModule patterns, consulted in order so
+// earlier entries take priority over later, more general ones.
+type moduleVerbosity struct {
+	patterns []modulePattern
+}
+
+func (mv *moduleVerbosity) lookup(fileName string) (int32, bool) {
+	base := strings.TrimSuffix(path.Base(fileName), ".go")
+	qualified := strings.TrimSuffix(path.Join(path.Base(path.Dir(fileName)), path.Base(fileName)), ".go")
+	for _, p := range mv.patterns {
+		if ok, _ := path.Match(p.glob, qualified); ok {
+			return p.level, true
+		}
+		if ok, _ := path.Match(p.glob, base); ok {
+			return p.level, true
+		}
+	}
+	return 0, false
+}
+
+var vmodule moduleVerbosity
+
+// VModule installs per-file/per-module overrides of the global verbosity
+// level (which Verbosity sets) compiled from a comma-separated "pattern=level" spec such as
+// "chatserver=3,chat/*=2". Each pattern is matched, using path.Match glob
+// syntax, against both the bare file name and the file name qualified by its
+// immediate parent directory (both without the .go suffix), so a pattern
+// like "chatserver" matches chatserver.go in any directory while "chat/*"
+// matches any file under a directory named chat. Patterns are consulted in
+// the order given, so list more specific patterns first.
+func VModule(spec string) func(Logger) Logger {
+	patterns := compileVModule(spec)
+	return func(l Logger) Logger {
+		vmodule = moduleVerbosity{patterns: patterns}
+		return l
+	}
+}
+
+func compileVModule(spec string) []modulePattern {
+	var patterns []modulePattern
+	for _, clause := range strings.Split(spec, ",") {
+		clause = strings.TrimSpace(clause)
+		if clause == "" {
+			continue
+		}
+		parts := strings.SplitN(clause, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		level, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			continue
+		}
+		patterns = append(patterns, modulePattern{glob: strings.TrimSpace(parts[0]), level: int32(level)})
+	}
+	return patterns
+}
+
+// Verbosity sets the global verbosity level consulted by V.
+func Verbosity(level int) func(Logger) Logger {
+	return func(l Logger) Logger {
+		globalVerbosity = int32(level)
+		return l
+	}
+}
+
+// backtraceSite is one "file:line" entry installed by LogBacktraceAt.
+type backtraceSite struct {
+	file string
+	line int
+}
+
+var backtraceSites []backtraceSite
+
+// LogBacktraceAt installs a comma-separated list of "file:line" sites. When a
+// log call originates at a listed site, its record gets a runtime.Stack dump
+// appended, same as the glog flag of the same name.
+func LogBacktraceAt(spec string) func(Logger) Logger {
+	var sites []backtraceSite
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		idx := strings.LastIndex(entry, ":")
+		if idx < 0 {
+			continue
+		}
+		line, err := strconv.Atoi(entry[idx+1:])
+		if err != nil {
+			continue
+		}
+		sites = append(sites, backtraceSite{file: entry[:idx], line: line})
+	}
+	return func(l Logger) Logger {
+		backtraceSites = sites
+		return l
+	}
+}
+
+// backtraceIfNeeded returns a runtime.Stack dump to append to the record if
+// fileName:lineNum matches a LogBacktraceAt site, or "" otherwise.
+func backtraceIfNeeded(fileName string, lineNum int) string {
+	if len(backtraceSites) == 0 {
+		return ""
+	}
+	base := path.Base(fileName)
+	for _, site := range backtraceSites {
+		if site.line == lineNum && (site.file == fileName || site.file == base) {
+			buf := make([]byte, 1<<16)
+			n := runtime.Stack(buf, false)
+			return fmt.Sprintf("\n%s", buf[:n])
+		}
+	}
+	return ""
+}
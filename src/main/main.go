@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"tao"
 )
@@ -16,17 +17,33 @@ type ChatServer struct {
 	*tao.Server
 }
 
+// connLoggers caches the per-connection child logger created on connect, so
+// OnError/OnClose tag their lines with the same conn_id instead of each
+// deriving their own. chat.ProcessMessage lives outside this repo, so
+// traffic logs it emits aren't reached by this cache.
+var connLoggers sync.Map // tao.WriteCloser -> logger.Logger
+
+func connLogger(conn tao.WriteCloser) logger.Logger {
+	if l, ok := connLoggers.Load(conn); ok {
+		return l.(logger.Logger)
+	}
+	return logger.With("conn_id", fmt.Sprintf("%p", conn))
+}
+
 // NewChatServer returns a ChatServer.
 func NewChatServer() *ChatServer {
 	onConnectOption := tao.OnConnectOption(func(conn tao.WriteCloser) bool {
-		logger.Infoln("on connect")
+		l := logger.With("conn_id", fmt.Sprintf("%p", conn))
+		connLoggers.Store(conn, l)
+		l.Infow("on connect")
 		return true
 	})
 	onErrorOption := tao.OnErrorOption(func(conn tao.WriteCloser) {
-		logger.Infoln("on error")
+		connLogger(conn).Infow("on error")
 	})
 	onCloseOption := tao.OnCloseOption(func(conn tao.WriteCloser) {
-		logger.Infoln("close chat client")
+		connLogger(conn).Infow("close chat client")
+		connLoggers.Delete(conn)
 	})
 	return &ChatServer{
 		tao.NewServer(onConnectOption, onErrorOption, onCloseOption),